@@ -0,0 +1,217 @@
+package pandora
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	stdio "io"
+
+	"go.octolab.org/encoding"
+	"go.octolab.org/io"
+)
+
+// TarWriter bundles named, independently serialized and transformed
+// entries into a single tar archive, mirroring the archive/tar package.
+// Each entry carries its own framing header (see writeHeader), so
+// entries may mix serializers and transformers freely within one
+// archive.
+type TarWriter struct {
+	tw  *tar.Writer
+	s   serializer
+	tt  []transformer
+	err error
+}
+
+// NewTarWriter returns a TarWriter whose entries are encoded with s/tt
+// by default; use AddAs to override the chain for a specific entry.
+func NewTarWriter(w io.Writer, s serializer, tt ...transformer) *TarWriter {
+	return &TarWriter{tw: tar.NewWriter(w), s: s, tt: tt}
+}
+
+// Add encodes v with the writer's default serializer/transformer chain
+// and appends it as a new entry named name. It is a no-op once a prior
+// Add, AddAs or Close has failed; check the error returned by Close.
+func (t *TarWriter) Add(name string, v interface{}) *TarWriter {
+	return t.AddAs(name, v, t.s, t.tt...)
+}
+
+// AddAs encodes v with s/tt, independently of the writer's default
+// chain, and appends it as a new entry named name. It lets a single
+// archive bundle entries encoded differently, e.g.
+// NewTarWriter(w, JSON, GZIP).Add("users.json.gz", users).
+// AddAs("events.msgpack.zst", events, MSGPACK, ZSTD).
+func (t *TarWriter) AddAs(name string, v interface{}, s serializer, tt ...transformer) *TarWriter {
+	if t.err != nil {
+		return t
+	}
+
+	var buf bytes.Buffer
+	if err := writeHeader(&buf, s.pack(tt...)); err != nil {
+		t.err = err
+		return t
+	}
+
+	enc, _ := s.Encoder(&buf, tt...)
+	if err := enc.Encode(v); err != nil {
+		t.err = err
+		return t
+	}
+	if err := enc.Close(); err != nil {
+		t.err = err
+		return t
+	}
+
+	if err := t.tw.WriteHeader(&tar.Header{Name: name, Size: int64(buf.Len()), Mode: 0644}); err != nil {
+		t.err = err
+		return t
+	}
+	if _, err := t.tw.Write(buf.Bytes()); err != nil {
+		t.err = err
+	}
+	return t
+}
+
+// Close flushes the tar footer, or returns the first error encountered
+// by Add/AddAs.
+func (t *TarWriter) Close() error {
+	if t.err != nil {
+		return t.err
+	}
+	t.err = t.tw.Close()
+	return t.err
+}
+
+// TarReader iterates the entries of a tar archive written by TarWriter,
+// decoding each with the serializer/transformer chain carried in its own
+// framing header, so mixed-encoding archives read back correctly.
+type TarReader struct {
+	tr *tar.Reader
+}
+
+// NewTarReader returns a TarReader that decodes entries read from r.
+func NewTarReader(r io.Reader) *TarReader {
+	return &TarReader{tr: tar.NewReader(r)}
+}
+
+// Next advances to the next entry and returns its name and a Decoder
+// for its payload. It returns io.EOF once the archive is exhausted.
+func (t *TarReader) Next() (string, encoding.DecodeCloser, error) {
+	header, err := t.tr.Next()
+	if err != nil {
+		return "", nil, err
+	}
+
+	format, err := readHeader(t.tr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var s serializer
+	return header.Name, s.Decoder(t.tr, format), nil
+}
+
+// ZipWriter bundles named, independently serialized and transformed
+// entries into a single zip archive, mirroring the archive/zip package.
+// Each entry carries its own framing header (see writeHeader), so
+// entries may mix serializers and transformers freely within one
+// archive.
+type ZipWriter struct {
+	zw  *zip.Writer
+	s   serializer
+	tt  []transformer
+	err error
+}
+
+// NewZipWriter returns a ZipWriter whose entries are encoded with s/tt
+// by default; use AddAs to override the chain for a specific entry.
+func NewZipWriter(w io.Writer, s serializer, tt ...transformer) *ZipWriter {
+	return &ZipWriter{zw: zip.NewWriter(w), s: s, tt: tt}
+}
+
+// Add encodes v with the writer's default serializer/transformer chain
+// and appends it as a new entry named name. It is a no-op once a prior
+// Add, AddAs or Close has failed; check the error returned by Close.
+func (z *ZipWriter) Add(name string, v interface{}) *ZipWriter {
+	return z.AddAs(name, v, z.s, z.tt...)
+}
+
+// AddAs encodes v with s/tt, independently of the writer's default
+// chain, and appends it as a new entry named name. It lets a single
+// archive bundle entries encoded differently, e.g.
+// NewZipWriter(w, JSON, GZIP).Add("users.json.gz", users).
+// AddAs("events.msgpack.zst", events, MSGPACK, ZSTD).
+func (z *ZipWriter) AddAs(name string, v interface{}, s serializer, tt ...transformer) *ZipWriter {
+	if z.err != nil {
+		return z
+	}
+
+	entry, err := z.zw.Create(name)
+	if err != nil {
+		z.err = err
+		return z
+	}
+
+	if err := writeHeader(entry, s.pack(tt...)); err != nil {
+		z.err = err
+		return z
+	}
+
+	enc, _ := s.Encoder(entry, tt...)
+	if err := enc.Encode(v); err != nil {
+		z.err = err
+		return z
+	}
+	z.err = enc.Close()
+	return z
+}
+
+// Close flushes the zip central directory, or returns the first error
+// encountered by Add/AddAs.
+func (z *ZipWriter) Close() error {
+	if z.err != nil {
+		return z.err
+	}
+	z.err = z.zw.Close()
+	return z.err
+}
+
+// ZipReader iterates the entries of a zip archive written by ZipWriter,
+// decoding each with the serializer/transformer chain carried in its own
+// framing header, so mixed-encoding archives read back correctly.
+type ZipReader struct {
+	files []*zip.File
+	next  int
+}
+
+// NewZipReader returns a ZipReader that decodes entries read from r,
+// whose total size is size.
+func NewZipReader(r stdio.ReaderAt, size int64) (*ZipReader, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return &ZipReader{files: archive.File}, nil
+}
+
+// Next advances to the next entry and returns its name and a Decoder
+// for its payload. It returns io.EOF once the archive is exhausted.
+func (z *ZipReader) Next() (string, encoding.DecodeCloser, error) {
+	if z.next >= len(z.files) {
+		return "", nil, stdio.EOF
+	}
+	file := z.files[z.next]
+	z.next++
+
+	rc, err := file.Open()
+	if err != nil {
+		return "", nil, err
+	}
+
+	format, err := readHeader(rc)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var s serializer
+	return file.Name, s.Decoder(rc, format), nil
+}
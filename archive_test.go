@@ -0,0 +1,110 @@
+package pandora
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestTarWriterReaderRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+
+	users := payload{Name: "users", Value: 1}
+	events := payload{Name: "events", Value: 2}
+
+	tw := NewTarWriter(&buf, JSON, GZIP)
+	tw.Add("users.json.gz", users)
+	tw.AddAs("events.msgpack.zst", events, MSGPACK, ZSTD)
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	tr := NewTarReader(&buf)
+
+	name, dec, err := tr.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if name != "users.json.gz" {
+		t.Fatalf("got name %q, want %q", name, "users.json.gz")
+	}
+	var gotUsers payload
+	if err := dec.Decode(&gotUsers); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if gotUsers != users {
+		t.Fatalf("got %+v, want %+v", gotUsers, users)
+	}
+
+	name, dec, err = tr.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if name != "events.msgpack.zst" {
+		t.Fatalf("got name %q, want %q", name, "events.msgpack.zst")
+	}
+	var gotEvents payload
+	if err := dec.Decode(&gotEvents); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if gotEvents != events {
+		t.Fatalf("got %+v, want %+v", gotEvents, events)
+	}
+
+	if _, _, err := tr.Next(); err != io.EOF {
+		t.Fatalf("got err %v, want io.EOF", err)
+	}
+}
+
+func TestZipWriterReaderRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+
+	users := payload{Name: "users", Value: 1}
+	events := payload{Name: "events", Value: 2}
+
+	zw := NewZipWriter(&buf, JSON, GZIP)
+	zw.Add("users.json.gz", users)
+	zw.AddAs("events.msgpack.zst", events, MSGPACK, ZSTD)
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	zr, err := NewZipReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("NewZipReader: %v", err)
+	}
+
+	name, dec, err := zr.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if name != "users.json.gz" {
+		t.Fatalf("got name %q, want %q", name, "users.json.gz")
+	}
+	var gotUsers payload
+	if err := dec.Decode(&gotUsers); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if gotUsers != users {
+		t.Fatalf("got %+v, want %+v", gotUsers, users)
+	}
+
+	name, dec, err = zr.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if name != "events.msgpack.zst" {
+		t.Fatalf("got name %q, want %q", name, "events.msgpack.zst")
+	}
+	var gotEvents payload
+	if err := dec.Decode(&gotEvents); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if gotEvents != events {
+		t.Fatalf("got %+v, want %+v", gotEvents, events)
+	}
+
+	if _, _, err := zr.Next(); err != io.EOF {
+		t.Fatalf("got err %v, want io.EOF", err)
+	}
+}
@@ -0,0 +1,135 @@
+package pandora
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"hash"
+	"hash/crc32"
+	stdio "io"
+
+	"go.octolab.org/errors"
+	"go.octolab.org/io"
+)
+
+const (
+	SHA256 transformer = "sha256"
+	CRC32C transformer = "crc32c"
+
+	ErrChecksumMismatch errors.Message = "checksum mismatch"
+)
+
+func init() {
+	RegisterTransformer(string(SHA256), TransformerIO{
+		Input: func(input io.ReadCloser) (io.ReadCloser, error) {
+			return &checksumReader{input: input, hash: sha256.New(), size: sha256.Size}, nil
+		},
+		Output: func(output io.WriteCloser) (io.WriteCloser, error) {
+			return &checksumWriter{output: output, hash: sha256.New()}, nil
+		},
+	})
+	RegisterTransformer(string(CRC32C), TransformerIO{
+		Input: func(input io.ReadCloser) (io.ReadCloser, error) {
+			return &checksumReader{input: input, hash: crc32.New(crc32.MakeTable(crc32.Castagnoli)), size: crc32.Size}, nil
+		},
+		Output: func(output io.WriteCloser) (io.WriteCloser, error) {
+			return &checksumWriter{output: output, hash: crc32.New(crc32.MakeTable(crc32.Castagnoli))}, nil
+		},
+	})
+}
+
+// checksumWriter tees every write through hash and appends its digest
+// to output on Close, so a checksumReader on the other end can verify
+// the stream wasn't corrupted.
+type checksumWriter struct {
+	output io.WriteCloser
+	hash   hash.Hash
+}
+
+func (c *checksumWriter) Write(p []byte) (int, error) {
+	n, err := c.output.Write(p)
+	if n > 0 {
+		c.hash.Write(p[:n])
+	}
+	return n, err
+}
+
+func (c *checksumWriter) Close() error {
+	if _, err := c.output.Write(c.hash.Sum(nil)); err != nil {
+		return err
+	}
+	return c.output.Close()
+}
+
+// checksumReader holds back the trailing len(digest) bytes of input,
+// since they are the digest appended by checksumWriter rather than
+// payload, and verifies them against the hash of everything released
+// once input is exhausted.
+type checksumReader struct {
+	input io.ReadCloser
+	hash  hash.Hash
+	size  int
+
+	tail  []byte
+	ready []byte
+	eof   bool
+	done  bool
+	err   error
+}
+
+func (c *checksumReader) Read(p []byte) (int, error) {
+	for len(c.ready) == 0 && !c.done {
+		if err := c.pull(); err != nil {
+			return 0, err
+		}
+	}
+	if len(c.ready) == 0 {
+		return 0, stdio.EOF
+	}
+
+	n := copy(p, c.ready)
+	c.hash.Write(c.ready[:n])
+	c.ready = c.ready[n:]
+	return n, nil
+}
+
+func (c *checksumReader) pull() error {
+	if c.eof {
+		if !bytes.Equal(c.tail, c.hash.Sum(nil)) {
+			c.err = ErrChecksumMismatch
+		}
+		c.done = true
+		return nil
+	}
+
+	buf := make([]byte, 4096)
+	n, err := c.input.Read(buf)
+	if n > 0 {
+		c.tail = append(c.tail, buf[:n]...)
+		if excess := len(c.tail) - c.size; excess > 0 {
+			c.ready = append(c.ready, c.tail[:excess]...)
+			c.tail = append([]byte(nil), c.tail[excess:]...)
+		}
+	}
+	if err != nil {
+		if err != stdio.EOF {
+			return err
+		}
+		c.eof = true
+	}
+	return nil
+}
+
+func (c *checksumReader) Close() error {
+	buf := make([]byte, 4096)
+	for {
+		if _, err := c.Read(buf); err != nil {
+			break
+		}
+	}
+
+	closeErr := c.input.Close()
+	if c.err != nil {
+		return c.err
+	}
+	return closeErr
+}
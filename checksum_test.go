@@ -0,0 +1,54 @@
+package pandora
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSHA256RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+
+	enc, format := JSON.Encoder(&buf, SHA256)
+	in := payload{Name: "checksum", Value: 1}
+	if err := enc.Encode(in); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	var out payload
+	dec := JSON.Decoder(&buf, format)
+	if err := dec.Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if err := dec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if out != in {
+		t.Fatalf("got %+v, want %+v", out, in)
+	}
+}
+
+func TestSHA256CorruptedDigest(t *testing.T) {
+	var buf bytes.Buffer
+
+	enc, format := JSON.Encoder(&buf, SHA256)
+	if err := enc.Encode(payload{Name: "checksum", Value: 1}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	corrupted := buf.Bytes()
+	corrupted[len(corrupted)-1] ^= 0xFF
+
+	var out payload
+	dec := JSON.Decoder(bytes.NewReader(corrupted), format)
+	_ = dec.Decode(&out)
+	if err := dec.Close(); err != ErrChecksumMismatch {
+		t.Fatalf("got err %v, want %v", err, ErrChecksumMismatch)
+	}
+}
@@ -0,0 +1,201 @@
+// Package httpcodec exposes pandora's serializer/transformer chain to
+// HTTP handlers through content negotiation, the same way ad-hoc
+// GzipResponseWriter helpers expose a single compression codec.
+package httpcodec
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kamilsk/pandora"
+	"go.octolab.org/encoding"
+	"go.octolab.org/errors"
+)
+
+const (
+	ErrUnsupportedMediaType errors.Message = "unsupported media type"
+
+	mimeJSON    = "application/json"
+	mimeMsgpack = "application/msgpack"
+)
+
+var mimeMu sync.RWMutex
+
+var mimeToSerializer = map[string]string{
+	mimeJSON:    string(pandora.JSON),
+	mimeMsgpack: string(pandora.MSGPACK),
+}
+
+var serializerToMime = map[string]string{
+	string(pandora.JSON):    mimeJSON,
+	string(pandora.MSGPACK): mimeMsgpack,
+}
+
+// RegisterMime associates mimeType with the serializer registered in
+// pandora's registry under name (see pandora.RegisterSerializer), making
+// it selectable via Accept/Content-Type alongside JSON and MSGPACK. For
+// example, a PROTOJSON serializer registered with pandora.RegisterSerializer
+// can be exposed here as "application/protojson".
+func RegisterMime(mimeType, name string) {
+	mimeMu.Lock()
+	defer mimeMu.Unlock()
+	mimeToSerializer[mimeType] = name
+	serializerToMime[name] = mimeType
+}
+
+// Handler negotiates a serializer and transformer for the response based
+// on the request's Accept and Accept-Encoding headers, sets Content-Type,
+// Content-Encoding and Vary accordingly, and makes the resulting Encoder
+// available to next via Encoder(r). If nothing in the registry satisfies
+// Accept, it responds with 406 Not Acceptable and never calls next.
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enc, err := NewEncoder(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotAcceptable)
+			return
+		}
+		defer enc.Close()
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), encoderKey{}, enc)))
+	})
+}
+
+// Encoder returns the Encoder negotiated for r by Handler.
+func Encoder(r *http.Request) (encoding.EncodeCloser, bool) {
+	enc, ok := r.Context().Value(encoderKey{}).(encoding.EncodeCloser)
+	return enc, ok
+}
+
+// NewEncoder negotiates a serializer from Accept and a transformer from
+// Accept-Encoding, sets Content-Type, Content-Encoding and Vary on w, and
+// returns an Encoder that writes through the negotiated chain.
+func NewEncoder(w http.ResponseWriter, r *http.Request) (encoding.EncodeCloser, error) {
+	name, ok := negotiateSerializer(r.Header.Get("Accept"))
+	if !ok {
+		return nil, ErrUnsupportedMediaType
+	}
+	s, ok := pandora.Serializer(name)
+	if !ok {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	mimeMu.RLock()
+	mimeType := serializerToMime[name]
+	mimeMu.RUnlock()
+
+	header := w.Header()
+	header.Add("Vary", "Accept-Encoding")
+	header.Set("Content-Type", mimeType)
+
+	encodingName, hasEncoding := negotiateTransformer(r.Header.Get("Accept-Encoding"))
+	if !hasEncoding {
+		enc, _ := s.Encoder(w)
+		return enc, nil
+	}
+
+	tt, ok := pandora.Transformers(encodingName)
+	if !ok {
+		return nil, ErrUnsupportedMediaType
+	}
+	header.Set("Content-Encoding", encodingName)
+	enc, _ := s.Encoder(w, tt...)
+	return enc, nil
+}
+
+// NewDecoder builds a Decoder for r.Body from its Content-Type and
+// Content-Encoding headers.
+func NewDecoder(r *http.Request) (encoding.DecodeCloser, error) {
+	mimeType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, ErrUnsupportedMediaType
+	}
+	mimeMu.RLock()
+	name, ok := mimeToSerializer[mimeType]
+	mimeMu.RUnlock()
+	if !ok {
+		return nil, ErrUnsupportedMediaType
+	}
+	s, ok := pandora.Serializer(name)
+	if !ok {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	var tt []string
+	if header := r.Header.Get("Content-Encoding"); header != "" {
+		tt = splitList(header)
+		if _, ok := pandora.Transformers(tt...); !ok {
+			return nil, ErrUnsupportedMediaType
+		}
+	}
+
+	return s.Decoder(r.Body, pandora.Pack(name, tt...)), nil
+}
+
+type encoderKey struct{}
+
+func negotiateSerializer(accept string) (string, bool) {
+	if accept == "" {
+		return string(pandora.JSON), true
+	}
+
+	mimeMu.RLock()
+	defer mimeMu.RUnlock()
+
+	for _, candidate := range splitList(accept) {
+		if candidate == "*/*" {
+			return string(pandora.JSON), true
+		}
+		if name, known := mimeToSerializer[candidate]; known {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// negotiateTransformer picks the first Accept-Encoding candidate known to
+// pandora's transformer registry, so anything registered there via
+// pandora.RegisterTransformer (not just GZIP/ZSTD) is negotiable here.
+func negotiateTransformer(acceptEncoding string) (string, bool) {
+	for _, candidate := range splitList(acceptEncoding) {
+		if _, ok := pandora.Transformers(candidate); ok {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// splitList parses a comma-separated Accept/Accept-Encoding style header
+// into its candidate names, in the order given, dropping any candidate
+// whose q parameter is 0 (RFC 7231 §5.3.1: q=0 means "not acceptable").
+// Other parameters, including other q values, are not used for ranking.
+func splitList(header string) []string {
+	parts := strings.Split(header, ",")
+	out := make([]string, 0, len(parts))
+	for _, part := range parts {
+		name := part
+		q := 1.0
+		if i := strings.IndexByte(part, ';'); i >= 0 {
+			name = part[:i]
+			for _, param := range strings.Split(part[i+1:], ";") {
+				key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if ok && strings.EqualFold(strings.TrimSpace(key), "q") {
+					if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+						q = parsed
+					}
+				}
+			}
+		}
+
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || q == 0 {
+			continue
+		}
+		out = append(out, name)
+	}
+	return out
+}
@@ -0,0 +1,169 @@
+package httpcodec
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kamilsk/pandora"
+)
+
+type payload struct {
+	Name  string
+	Value int
+}
+
+func TestNegotiateSerializer(t *testing.T) {
+	cases := []struct {
+		name   string
+		accept string
+		want   string
+		ok     bool
+	}{
+		{name: "empty defaults to json", accept: "", want: string(pandora.JSON), ok: true},
+		{name: "wildcard defaults to json", accept: "*/*", want: string(pandora.JSON), ok: true},
+		{name: "exact msgpack", accept: mimeMsgpack, want: string(pandora.MSGPACK), ok: true},
+		{name: "quality params ignored for ranking", accept: "application/msgpack;q=0.9", want: string(pandora.MSGPACK), ok: true},
+		{name: "unknown media type", accept: "text/xml", want: "", ok: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := negotiateSerializer(c.accept)
+			if ok != c.ok || got != c.want {
+				t.Fatalf("negotiateSerializer(%q) = (%q, %v), want (%q, %v)", c.accept, got, ok, c.want, c.ok)
+			}
+		})
+	}
+}
+
+func TestNegotiateTransformerRespectsZeroQuality(t *testing.T) {
+	got, ok := negotiateTransformer("gzip;q=0, zstd")
+	if !ok {
+		t.Fatalf("negotiateTransformer: expected a match")
+	}
+	if got != string(pandora.ZSTD) {
+		t.Fatalf("got %q, want %q (gzip marked unacceptable with q=0)", got, pandora.ZSTD)
+	}
+}
+
+func TestNegotiateTransformerAllZeroQuality(t *testing.T) {
+	if _, ok := negotiateTransformer("gzip;q=0"); ok {
+		t.Fatalf("negotiateTransformer: expected no match when every candidate has q=0")
+	}
+}
+
+func TestNewEncoderJSONDefault(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+
+	enc, err := NewEncoder(rec, req)
+	if err != nil {
+		t.Fatalf("NewEncoder: %v", err)
+	}
+	if err := enc.Encode(payload{Name: "a", Value: 1}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if ct := rec.Header().Get("Content-Type"); ct != mimeJSON {
+		t.Fatalf("Content-Type = %q, want %q", ct, mimeJSON)
+	}
+	if ce := rec.Header().Get("Content-Encoding"); ce != "" {
+		t.Fatalf("Content-Encoding = %q, want empty", ce)
+	}
+	if v := rec.Header().Get("Vary"); v != "Accept-Encoding" {
+		t.Fatalf("Vary = %q, want %q", v, "Accept-Encoding")
+	}
+}
+
+func TestNewEncoderNegotiatesEncoding(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", mimeMsgpack)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+
+	enc, err := NewEncoder(rec, req)
+	if err != nil {
+		t.Fatalf("NewEncoder: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if ct := rec.Header().Get("Content-Type"); ct != mimeMsgpack {
+		t.Fatalf("Content-Type = %q, want %q", ct, mimeMsgpack)
+	}
+	if ce := rec.Header().Get("Content-Encoding"); ce != string(pandora.GZIP) {
+		t.Fatalf("Content-Encoding = %q, want %q", ce, pandora.GZIP)
+	}
+}
+
+func TestNewEncoderUnacceptable(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "text/xml")
+	rec := httptest.NewRecorder()
+
+	if _, err := NewEncoder(rec, req); err != ErrUnsupportedMediaType {
+		t.Fatalf("got err %v, want %v", err, ErrUnsupportedMediaType)
+	}
+}
+
+func TestHandlerNotAcceptable(t *testing.T) {
+	handler := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("next should not be called")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "text/xml")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotAcceptable {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotAcceptable)
+	}
+}
+
+func TestNewDecoder(t *testing.T) {
+	var buf bytes.Buffer
+	enc, _ := pandora.MSGPACK.Encoder(&buf, pandora.GZIP)
+	in := payload{Name: "b", Value: 2}
+	if err := enc.Encode(in); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(&buf))
+	req.Header.Set("Content-Type", mimeMsgpack)
+	req.Header.Set("Content-Encoding", string(pandora.GZIP))
+
+	dec, err := NewDecoder(req)
+	if err != nil {
+		t.Fatalf("NewDecoder: %v", err)
+	}
+	defer dec.Close()
+
+	var out payload
+	if err := dec.Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if out != in {
+		t.Fatalf("got %+v, want %+v", out, in)
+	}
+}
+
+func TestNewDecoderUnsupportedContentType(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(nil))
+	req.Header.Set("Content-Type", "text/xml")
+
+	if _, err := NewDecoder(req); err != ErrUnsupportedMediaType {
+		t.Fatalf("got err %v, want %v", err, ErrUnsupportedMediaType)
+	}
+}
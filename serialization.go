@@ -3,7 +3,9 @@ package pandora
 import (
 	"compress/gzip"
 	"encoding/json"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/DataDog/zstd"
 	msgpack "github.com/ugorji/go/codec"
@@ -22,7 +24,8 @@ const (
 	ErrUnknownSerializer  errors.Message = "unknown serializer"
 	ErrUnknownTransformer errors.Message = "unknown transformer"
 
-	sep = "|"
+	sep      = "|"
+	levelSep = ":"
 )
 
 type serializer string
@@ -32,14 +35,16 @@ func (s serializer) String() string { return string(s) }
 func (s serializer) Encoder(writer io.Writer, tt ...transformer) (encoding.EncodeCloser, string) {
 	format := s.pack(tt...)
 
+	registryMu.RLock()
 	serialize, known := serializers[s]
+	registryMu.RUnlock()
 	if !known {
 		return nopSerializer(func(interface{}) error { return ErrUnknownSerializer }), format
 	}
 
 	var transform io.WriteCloserChain = func(output io.WriteCloser) (io.WriteCloser, error) { return output, nil }
 	for _, t := range tt {
-		transformer, known := transformers[t]
+		transformer, known := resolveTransformer(t)
 		if !known {
 			return nopSerializer(func(interface{}) error { return ErrUnknownTransformer }), format
 		}
@@ -63,14 +68,16 @@ func (s serializer) pack(tt ...transformer) string {
 func (s serializer) Decoder(reader io.Reader, format string) encoding.DecodeCloser {
 	tt := s.unpack(format)
 
+	registryMu.RLock()
 	serialize, known := serializers[s]
+	registryMu.RUnlock()
 	if !known {
 		return nopSerializer(func(interface{}) error { return ErrUnknownSerializer })
 	}
 
 	var transform io.ReadCloserChain = func(input io.ReadCloser) (io.ReadCloser, error) { return input, nil }
 	for _, t := range tt {
-		transformer, known := transformers[t]
+		transformer, known := resolveTransformer(t)
 		if !known {
 			return nopSerializer(func(interface{}) error { return ErrUnknownTransformer })
 		}
@@ -110,10 +117,145 @@ func (fn nopSerializer) Close() error             { return nil }
 
 //
 
-var serializers = map[serializer]struct {
+// SerializerIO describes how to wrap a reader/writer pair into the
+// encoding.DecodeCloser/EncodeCloser produced by a serializer.
+type SerializerIO struct {
 	Input  func(io.ReadCloser, error) encoding.DecodeCloser
 	Output func(io.WriteCloser, error) encoding.EncodeCloser
-}{
+}
+
+// TransformerIO describes how to chain a reader/writer pair through
+// a transformer, e.g. compression or encryption.
+type TransformerIO struct {
+	Input  io.ReadCloserChain
+	Output io.WriteCloserChain
+}
+
+// RegisterSerializer adds or replaces a serializer under the given name,
+// making it available to Encoder/Decoder alongside MSGPACK and JSON.
+// It is safe for concurrent use, including concurrent use with Encoder,
+// Decoder and the other Register*/Transformers/Serializer lookups.
+func RegisterSerializer(name string, io SerializerIO) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	serializers[serializer(name)] = io
+}
+
+// RegisterTransformer adds or replaces a transformer under the given name,
+// making it available to Encoder/Decoder alongside GZIP and ZSTD.
+// It is safe for concurrent use, including concurrent use with Encoder,
+// Decoder and the other Register*/Transformers/Serializer lookups.
+func RegisterTransformer(name string, io TransformerIO) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	transformers[transformer(name)] = io
+}
+
+// Serializer resolves name against the registry, reporting whether it is
+// known. It lets other packages, e.g. pandora/httpcodec, build an Encoder
+// or Decoder without depending on the unexported serializer type.
+func Serializer(name string) (serializer, bool) {
+	s := serializer(name)
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	_, known := serializers[s]
+	return s, known
+}
+
+// Transformers resolves names against the registry, reporting whether
+// all of them are known. It lets other packages, e.g. pandora/httpcodec,
+// build the transformer chain passed to Encoder/Decoder without depending
+// on the unexported transformer type.
+func Transformers(names ...string) ([]transformer, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	tt := make([]transformer, 0, len(names))
+	for _, name := range names {
+		t := transformer(name)
+		if _, known := transformers[t]; !known {
+			return nil, false
+		}
+		tt = append(tt, t)
+	}
+	return tt, true
+}
+
+// Pack joins a serializer name and transformer names into the format
+// string expected by Decoder, e.g. Pack("json", "gzip") == "json|gzip".
+func Pack(name string, tt ...string) string {
+	data := make([]string, 0, len(tt)+1)
+	data = append(data, name)
+	data = append(data, tt...)
+	return strings.Join(data, sep)
+}
+
+// GzipLevel returns a GZIP variant that compresses at the given level
+// (see compress/gzip for valid values) instead of gzip.BestCompression.
+// The level is encoded into the pack format as "gzip:<level>".
+func GzipLevel(level int) transformer {
+	name := transformer(string(GZIP) + levelSep + strconv.Itoa(level))
+
+	registryMu.RLock()
+	input := transformers[GZIP].Input
+	registryMu.RUnlock()
+
+	RegisterTransformer(string(name), TransformerIO{
+		Input: input,
+		Output: func(output io.WriteCloser) (io.WriteCloser, error) {
+			encoder, err := gzip.NewWriterLevel(output, level)
+			return io.CascadeWriteCloser(encoder, output), err
+		},
+	})
+	return name
+}
+
+// ZstdLevel returns a ZSTD variant that compresses at the given level
+// instead of zstd.BestCompression. The level is encoded into the pack
+// format as "zstd:<level>".
+func ZstdLevel(level int) transformer {
+	name := transformer(string(ZSTD) + levelSep + strconv.Itoa(level))
+
+	registryMu.RLock()
+	input := transformers[ZSTD].Input
+	registryMu.RUnlock()
+
+	RegisterTransformer(string(name), TransformerIO{
+		Input: input,
+		Output: func(output io.WriteCloser) (io.WriteCloser, error) {
+			return io.CascadeWriteCloser(zstd.NewWriterLevel(output, level), output), nil
+		},
+	})
+	return name
+}
+
+// resolveTransformer looks up t as registered; if it carries an
+// unregistered "<name>:<level>" suffix (e.g. decoding a stream produced
+// by a process that called GzipLevel/ZstdLevel but never registered it
+// here), it falls back to the base transformer, whose Input is
+// level-agnostic.
+func resolveTransformer(t transformer) (TransformerIO, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if tt, known := transformers[t]; known {
+		return tt, true
+	}
+	if i := strings.Index(string(t), levelSep); i >= 0 {
+		tt, known := transformers[transformer(string(t)[:i])]
+		return tt, known
+	}
+	return TransformerIO{}, false
+}
+
+//
+
+// registryMu guards serializers and transformers: Register* calls may
+// happen concurrently with each other and with Encoder/Decoder/GzipLevel/
+// ZstdLevel/Serializer/Transformers lookups once a service is running.
+var registryMu sync.RWMutex
+
+var serializers = map[serializer]SerializerIO{
 	MSGPACK: {
 		Input: func(input io.ReadCloser, err error) encoding.DecodeCloser {
 			if err != nil {
@@ -144,10 +286,7 @@ var serializers = map[serializer]struct {
 	},
 }
 
-var transformers = map[transformer]struct {
-	Input  io.ReadCloserChain
-	Output io.WriteCloserChain
-}{
+var transformers = map[transformer]TransformerIO{
 	GZIP: {
 		Input: func(input io.ReadCloser) (io.ReadCloser, error) {
 			decoder, err := gzip.NewReader(input)
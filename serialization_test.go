@@ -0,0 +1,69 @@
+package pandora
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+type payload struct {
+	Name  string
+	Value int
+}
+
+func TestGzipLevelRoundTrip(t *testing.T) {
+	level := GzipLevel(1)
+
+	var buf bytes.Buffer
+	enc, format := JSON.Encoder(&buf, level)
+	in := payload{Name: "gzip", Value: 1}
+	if err := enc.Encode(in); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	var out payload
+	dec := JSON.Decoder(&buf, format)
+	if err := dec.Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if err := dec.Close(); err != nil {
+		t.Fatalf("Decode Close: %v", err)
+	}
+
+	if out != in {
+		t.Fatalf("got %+v, want %+v", out, in)
+	}
+}
+
+// TestGzipLevelConcurrent exercises GzipLevel racing against itself and
+// against Encoder/Decoder, which touch the same registry; run with
+// -race to catch a regression of the unsynchronized map access it fixes.
+func TestGzipLevelConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(level int) {
+			defer wg.Done()
+
+			tr := GzipLevel(level%9 + 1)
+
+			var buf bytes.Buffer
+			enc, format := JSON.Encoder(&buf, tr)
+			if err := enc.Encode(payload{Name: "concurrent", Value: level}); err != nil {
+				t.Errorf("Encode: %v", err)
+				return
+			}
+			if err := enc.Close(); err != nil {
+				t.Errorf("Close: %v", err)
+				return
+			}
+			if err := JSON.Decoder(&buf, format).Close(); err != nil {
+				t.Errorf("Decode Close: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
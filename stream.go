@@ -0,0 +1,199 @@
+package pandora
+
+import (
+	"bytes"
+	"encoding/binary"
+	stdio "io"
+
+	"go.octolab.org/encoding"
+	"go.octolab.org/errors"
+	"go.octolab.org/io"
+)
+
+const (
+	// magic identifies a pandora stream, similar in spirit to git's loose
+	// object format: a short header describing what follows, here a
+	// serializer/transformer chain instead of a type and size.
+	magic   = "PNDR"
+	version = 1
+
+	ErrInvalidHeader errors.Message = "invalid stream header"
+)
+
+// StreamEncoder writes a sequence of length-prefixed records to an
+// underlying stream that was opened with a single framing header,
+// produced by EncoderStream or Create.
+type StreamEncoder struct {
+	output io.WriteCloser
+	build  func(io.WriteCloser, error) encoding.EncodeCloser
+}
+
+// Encode serializes v and appends it to the stream as a new
+// length-prefixed record; the length prefix and record both pass through
+// the stream's transformer chain.
+func (e *StreamEncoder) Encode(v interface{}) error {
+	var buf bytes.Buffer
+
+	record := e.build(io.ToWriteCloser(&buf), nil)
+	if err := record.Encode(v); err != nil {
+		return err
+	}
+	if err := record.Close(); err != nil {
+		return err
+	}
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(buf.Len()))
+	if _, err := e.output.Write(length[:]); err != nil {
+		return err
+	}
+	_, err := e.output.Write(buf.Bytes())
+	return err
+}
+
+// Close closes the underlying transform chain and writer.
+func (e *StreamEncoder) Close() error { return e.output.Close() }
+
+// StreamDecoder reads the sequence of length-prefixed records written by
+// a StreamEncoder, produced by DecoderStream or Open.
+type StreamDecoder struct {
+	input io.ReadCloser
+	build func(io.ReadCloser, error) encoding.DecodeCloser
+}
+
+// Decode reads the next record from the stream into v. It returns
+// io.EOF once the stream is exhausted.
+func (d *StreamDecoder) Decode(v interface{}) error {
+	var length [4]byte
+	if _, err := stdio.ReadFull(d.input, length[:]); err != nil {
+		return err
+	}
+
+	record := make([]byte, binary.BigEndian.Uint32(length[:]))
+	if _, err := stdio.ReadFull(d.input, record); err != nil {
+		return err
+	}
+
+	decoder := d.build(io.ToReadCloser(bytes.NewReader(record)), nil)
+	defer decoder.Close()
+	return decoder.Decode(v)
+}
+
+// Close closes the underlying transform chain and reader.
+func (d *StreamDecoder) Close() error { return d.input.Close() }
+
+// EncoderStream writes the framing header (magic, version, format string)
+// to writer and returns a StreamEncoder that appends records to it
+// through the s/tt chain, so a later Open doesn't need format passed
+// out-of-band.
+func (s serializer) EncoderStream(writer io.Writer, tt ...transformer) (*StreamEncoder, error) {
+	if err := writeHeader(writer, s.pack(tt...)); err != nil {
+		return nil, err
+	}
+
+	registryMu.RLock()
+	serialize, known := serializers[s]
+	registryMu.RUnlock()
+	if !known {
+		return nil, ErrUnknownSerializer
+	}
+
+	var transform io.WriteCloserChain = func(output io.WriteCloser) (io.WriteCloser, error) { return output, nil }
+	for _, t := range tt {
+		tr, known := resolveTransformer(t)
+		if !known {
+			return nil, ErrUnknownTransformer
+		}
+		transform = transform.Chain(tr.Output)
+	}
+
+	output, err := transform(io.ToWriteCloser(writer))
+	if err != nil {
+		return nil, err
+	}
+
+	return &StreamEncoder{output: output, build: serialize.Output}, nil
+}
+
+// DecoderStream reads the framing header written by EncoderStream off
+// reader and returns a StreamDecoder for the records that follow. The
+// serializer and transformer chain are taken from the header, not from
+// the receiver.
+func (s serializer) DecoderStream(reader io.Reader) (*StreamDecoder, error) {
+	format, err := readHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+	tt := s.unpack(format)
+
+	registryMu.RLock()
+	serialize, known := serializers[s]
+	registryMu.RUnlock()
+	if !known {
+		return nil, ErrUnknownSerializer
+	}
+
+	var transform io.ReadCloserChain = func(input io.ReadCloser) (io.ReadCloser, error) { return input, nil }
+	for _, t := range tt {
+		tr, known := resolveTransformer(t)
+		if !known {
+			return nil, ErrUnknownTransformer
+		}
+		transform = transform.Chain(tr.Input)
+	}
+
+	input, err := transform(io.ToReadCloser(reader))
+	if err != nil {
+		return nil, err
+	}
+
+	return &StreamDecoder{input: input, build: serialize.Input}, nil
+}
+
+// Create opens writer as a pandora stream: it writes the framing header
+// for s/tt and returns a StreamEncoder ready to accept records.
+func Create(writer io.Writer, s serializer, tt ...transformer) (*StreamEncoder, error) {
+	return s.EncoderStream(writer, tt...)
+}
+
+// Open sniffs the framing header off reader and returns a StreamDecoder
+// for the records that follow, resolving the serializer/transformer
+// chain from the registry without any out-of-band format string.
+func Open(reader io.Reader) (*StreamDecoder, error) {
+	var s serializer
+	return s.DecoderStream(reader)
+}
+
+func writeHeader(w io.Writer, format string) error {
+	if _, err := w.Write([]byte(magic)); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{version}); err != nil {
+		return err
+	}
+
+	var length [2]byte
+	binary.BigEndian.PutUint16(length[:], uint16(len(format)))
+	if _, err := w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err := w.Write([]byte(format))
+	return err
+}
+
+func readHeader(r io.Reader) (string, error) {
+	head := make([]byte, len(magic)+1+2)
+	if _, err := stdio.ReadFull(r, head); err != nil {
+		return "", err
+	}
+	if string(head[:len(magic)]) != magic || head[len(magic)] != version {
+		return "", ErrInvalidHeader
+	}
+
+	length := binary.BigEndian.Uint16(head[len(magic)+1:])
+	format := make([]byte, length)
+	if _, err := stdio.ReadFull(r, format); err != nil {
+		return "", err
+	}
+	return string(format), nil
+}
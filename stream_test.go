@@ -0,0 +1,99 @@
+package pandora
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestCreateOpenRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+
+	enc, err := Create(&buf, JSON, GZIP)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	records := []payload{{Name: "a", Value: 1}, {Name: "b", Value: 2}}
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	dec, err := Open(&buf)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer dec.Close()
+
+	for _, want := range records {
+		var got payload
+		if err := dec.Decode(&got); err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+		if got != want {
+			t.Fatalf("got %+v, want %+v", got, want)
+		}
+	}
+	if err := dec.Decode(&payload{}); err != io.EOF {
+		t.Fatalf("got err %v, want io.EOF", err)
+	}
+}
+
+// TestCreateOpenConcurrent exercises Create/Open (and the EncoderStream/
+// DecoderStream they delegate to) racing against RegisterSerializer, which
+// writes the same registry under registryMu; run with -race to catch a
+// regression of the unsynchronized map reads it fixes.
+func TestCreateOpenConcurrent(t *testing.T) {
+	jsonIO := serializers[JSON]
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 8; i++ {
+			RegisterSerializer("json", jsonIO)
+		}
+	}()
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			var buf bytes.Buffer
+			enc, err := Create(&buf, JSON, GZIP)
+			if err != nil {
+				t.Errorf("Create: %v", err)
+				return
+			}
+			if err := enc.Encode(payload{Name: "concurrent", Value: i}); err != nil {
+				t.Errorf("Encode: %v", err)
+				return
+			}
+			if err := enc.Close(); err != nil {
+				t.Errorf("Close: %v", err)
+				return
+			}
+
+			dec, err := Open(&buf)
+			if err != nil {
+				t.Errorf("Open: %v", err)
+				return
+			}
+			defer dec.Close()
+
+			var out payload
+			if err := dec.Decode(&out); err != nil {
+				t.Errorf("Decode: %v", err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}